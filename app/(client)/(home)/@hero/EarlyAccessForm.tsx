@@ -1,11 +1,12 @@
 "use client";
 
 import {addToWaitlist} from "@/shared/actions/waitlist";
+import {useAttribution} from "@/shared/hooks/useAttribution";
 
 import React, {useActionState} from "react";
 import {ArrowRight} from 'lucide-react';
 
-export default function EarlyAccessForm() {
+export default function EarlyAccessForm({requireInvite}: { requireInvite?: boolean }) {
     const [state, action, pending] = useActionState(
         addToWaitlist, {
             success: false,
@@ -15,12 +16,17 @@ export default function EarlyAccessForm() {
             },
         }
     );
+    const attribution = useAttribution();
 
     return <>
         <form
             className="flex flex-col sm:flex-row gap-3 max-w-md"
             action={action}
         >
+            <input type="hidden" name="source" value="hero"/>
+            {Object.entries(attribution).map(([name, value]) =>
+                <input key={name} type="hidden" name={name} value={value}/>
+            )}
             <input
                 type="email"
                 name="email"
@@ -29,6 +35,14 @@ export default function EarlyAccessForm() {
                 defaultValue={state.data.email}
                 required
             />
+            {requireInvite && <input
+                type="text"
+                name="invite"
+                placeholder="Invite code"
+                className="flex-1 bg-white border border-slate-300 rounded-full px-6 py-3.5 text-slate-900 placeholder-slate-400 focus:outline-none focus:ring-2 focus:ring-blue-500/20 focus:border-blue-500 transition-all shadow-sm"
+                defaultValue={state.data.invite}
+                required
+            />}
             <button
                 className="px-8 py-3.5 rounded-full bg-slate-900 text-white font-bold hover:bg-slate-800 hover:shadow-lg hover:scale-105 transition-all duration-300 flex items-center justify-center gap-2 group shadow-md"
                 type="submit" disabled={pending}
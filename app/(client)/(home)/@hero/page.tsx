@@ -1,6 +1,7 @@
 import styles from "./page.module.css";
 import EarlyAccessForm from "./EarlyAccessForm";
 import VisualCenterPiece from "./VisualCenterPiece";
+import env from "@/shared/lib/env";
 
 import {Sparkles} from 'lucide-react';
 import React from "react";
@@ -46,7 +47,7 @@ export default function Page() {
                     Payoneer, and banks with <strong>Mid-Market (Wholesale) Rates</strong>.
                 </p>
 
-                <EarlyAccessForm/>
+                <EarlyAccessForm requireInvite={env.waitlist.requireInvite}/>
 
                 <div className="mt-8 flex items-center gap-4 text-sm text-slate-500">
                     <div className="flex -space-x-2">
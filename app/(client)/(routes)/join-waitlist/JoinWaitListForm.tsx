@@ -1,10 +1,11 @@
 "use client";
 
 import {addToWaitlist} from "@/shared/actions/waitlist";
+import {useAttribution} from "@/shared/hooks/useAttribution";
 
 import {useActionState} from "react";
 
-const JoinWaitlistForm = () => {
+const JoinWaitlistForm = ({requireInvite}: { requireInvite?: boolean }) => {
     const [state, action, pending] = useActionState(
         addToWaitlist, {
             success: false,
@@ -14,8 +15,13 @@ const JoinWaitlistForm = () => {
             },
         }
     );
+    const attribution = useAttribution();
 
     return <form className="space-y-4" action={action}>
+        <input type="hidden" name="source" value="join-waitlist"/>
+        {Object.entries(attribution).map(([name, value]) =>
+            <input key={name} type="hidden" name={name} value={value}/>
+        )}
         <input
             type="email"
             name="email"
@@ -24,6 +30,14 @@ const JoinWaitlistForm = () => {
             defaultValue={state.data.email}
             required
         />
+        {requireInvite && <input
+            type="text"
+            name="invite"
+            placeholder="Enter your invite code"
+            className="w-full px-6 py-4 rounded-xl bg-slate-50 border border-slate-200 focus:border-blue-500 focus:ring-2 focus:ring-blue-200 outline-none transition-all text-lg"
+            defaultValue={state.data.invite}
+            required
+        />}
         <p aria-live="polite">{state?.message}</p>
         <button
             className="w-full py-4 bg-blue-600 text-white rounded-xl font-bold hover:bg-blue-500 transition-colors text-lg shadow-lg shadow-blue-500/20"
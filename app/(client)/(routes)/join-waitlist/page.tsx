@@ -1,4 +1,5 @@
 import JoinWaitlistForm from "./JoinWaitListForm";
+import env from "@/shared/lib/env";
 
 import React from 'react';
 
@@ -16,7 +17,7 @@ const JoinWaitlist: React.FC = () => {
                     We are rolling out access gradually. Secure your spot in line to experience the future of finance.
                 </p>
 
-                <JoinWaitlistForm/>
+                <JoinWaitlistForm requireInvite={env.waitlist.requireInvite}/>
 
                 <p className="text-xs text-slate-400 mt-6">
                     By joining, you agree to our Terms of Service and Privacy Policy.
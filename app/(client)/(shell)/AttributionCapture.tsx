@@ -0,0 +1,10 @@
+"use client";
+
+import {captureAttribution} from "@/shared/hooks/useAttribution";
+
+import {useEffect} from "react";
+
+export default function AttributionCapture() {
+    useEffect(captureAttribution, []);
+    return null;
+}
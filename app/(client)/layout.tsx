@@ -2,6 +2,7 @@ import "./globals.css";
 import Header from "./(shell)/Header";
 import Footer from "./(shell)/Footer";
 import Vercel from "./(shell)/Vercel";
+import AttributionCapture from "./(shell)/AttributionCapture";
 
 import React from "react";
 
@@ -43,6 +44,7 @@ export default function RootLayout(
             </div>
         </div>
         <Vercel/>
+        <AttributionCapture/>
         </body>
         </html>
     );
@@ -0,0 +1,37 @@
+import {requireAdmin} from "@/shared/lib/admin";
+import {ErrorCode, errorResponse} from "@/shared/lib/errors";
+import {getFlags, setFlagOverride} from "@/shared/lib/flags";
+
+import {z} from 'zod';
+import {NextRequest, NextResponse} from "next/server";
+
+const FlagSchema = z.object({
+    flag: z.string().regex(/^[a-z0-9-]+$/).max(64),
+    value: z.boolean().nullable(),
+});
+
+export const dynamic = "force-dynamic";
+
+export async function GET() {
+    const denied = await requireAdmin();
+    if (denied) {
+        return denied;
+    }
+    return NextResponse.json(await getFlags());
+}
+
+// {"flag": "signup-events", "value": false} overrides FEATURE_FLAGS, null clears the override
+export async function PUT(request: NextRequest) {
+    const denied = await requireAdmin();
+    if (denied) {
+        return denied;
+    }
+
+    const result = FlagSchema.safeParse(await request.json().catch(() => null));
+    if (!result.success) {
+        return errorResponse(ErrorCode.InvalidRequest, "flag must be a lowercase name and value a boolean or null");
+    }
+
+    await setFlagOverride(result.data.flag, result.data.value);
+    return NextResponse.json(await getFlags());
+}
@@ -0,0 +1,24 @@
+import db from "@/shared/lib/mongodb";
+import {requireAdmin} from "@/shared/lib/admin";
+
+import {NextResponse} from "next/server";
+
+export async function GET() {
+    const denied = await requireAdmin();
+    if (denied) {
+        return denied;
+    }
+
+    const [signups, reasons] = await Promise.all([
+        db.collection("waitlist").estimatedDocumentCount(),
+        db.collection("waitlist_attempts").aggregate<{ _id: string, count: number, lastSeen: Date }>([
+            {$group: {_id: "$reason", count: {$sum: 1}, lastSeen: {$max: "$createdAt"}}},
+            {$sort: {count: -1}},
+        ]).toArray(),
+    ]);
+
+    return NextResponse.json({
+        signups,
+        rejected: reasons.map(({_id, count, lastSeen}) => ({reason: _id, count, lastSeen})),
+    });
+}
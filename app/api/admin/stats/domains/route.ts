@@ -0,0 +1,31 @@
+import db from "@/shared/lib/mongodb";
+import {requireAdmin} from "@/shared/lib/admin";
+import {isFreeProvider} from "@/shared/lib/email";
+
+import {NextRequest, NextResponse} from "next/server";
+
+export async function GET(request: NextRequest) {
+    const denied = await requireAdmin();
+    if (denied) {
+        return denied;
+    }
+
+    const limit = Math.min(Math.max(Number(request.nextUrl.searchParams.get("limit")) || 100, 1), 1000);
+    const groups = await db.collection("waitlist").aggregate<{ _id: string, count: number }>([
+        {$project: {domain: {$toLower: {$arrayElemAt: [{$split: ["$email", "@"]}, -1]}}}},
+        {$group: {_id: "$domain", count: {$sum: 1}}},
+        {$sort: {count: -1, _id: 1}},
+    ]).toArray();
+
+    const totals = {corporate: 0, free: 0};
+    const domains = groups.map(({_id, count}) => {
+        const free = isFreeProvider(_id);
+        totals[free ? "free" : "corporate"] += count;
+        return {domain: _id, count, free};
+    });
+
+    return NextResponse.json({
+        totals,
+        domains: domains.slice(0, limit),
+    });
+}
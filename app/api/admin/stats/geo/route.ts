@@ -0,0 +1,26 @@
+import db from "@/shared/lib/mongodb";
+import {requireAdmin} from "@/shared/lib/admin";
+import {countBy} from "@/shared/lib/stats";
+
+import {NextResponse} from "next/server";
+
+export async function GET() {
+    const denied = await requireAdmin();
+    if (denied) {
+        return denied;
+    }
+
+    const [countries, regions] = await Promise.all([
+        countBy("country"),
+        db.collection("waitlist").aggregate<{ _id: { country: string, region: string | null }, count: number }>([
+            {$match: {country: {$ne: null}}},
+            {$group: {_id: {country: "$country", region: {$ifNull: ["$region", null]}}, count: {$sum: 1}}},
+            {$sort: {count: -1}},
+        ]).toArray(),
+    ]);
+
+    return NextResponse.json({
+        countries,
+        regions: regions.map(({_id, count}) => ({..._id, count})),
+    });
+}
@@ -0,0 +1,20 @@
+import {requireAdmin} from "@/shared/lib/admin";
+import {countBy} from "@/shared/lib/stats";
+
+import {NextResponse} from "next/server";
+
+export async function GET() {
+    const denied = await requireAdmin();
+    if (denied) {
+        return denied;
+    }
+
+    const [source, utmSource, utmMedium, utmCampaign] = await Promise.all([
+        countBy("source"),
+        countBy("utmSource"),
+        countBy("utmMedium"),
+        countBy("utmCampaign"),
+    ]);
+
+    return NextResponse.json({source, utmSource, utmMedium, utmCampaign});
+}
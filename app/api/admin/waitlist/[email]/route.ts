@@ -0,0 +1,44 @@
+import db from "@/shared/lib/mongodb";
+import {requireAdmin} from "@/shared/lib/admin";
+import {ErrorCode, errorResponse} from "@/shared/lib/errors";
+
+import {z} from 'zod';
+import {NextRequest, NextResponse} from "next/server";
+
+const AnnotationSchema = z.object({
+    notes: z.string().max(5000).optional(),
+    tags: z.array(z.string().trim().min(1).max(50)).max(50).optional(),
+}).refine(body => body.notes !== undefined || body.tags !== undefined);
+
+export async function PATCH(
+    request: NextRequest, ctx: RouteContext<"/api/admin/waitlist/[email]">
+) {
+    const denied = await requireAdmin();
+    if (denied) {
+        return denied;
+    }
+
+    const result = AnnotationSchema.safeParse(await request.json().catch(() => null));
+    if (!result.success) {
+        return errorResponse(ErrorCode.InvalidRequest, "Invalid notes or tags");
+    }
+
+    const {email} = await ctx.params;
+    const {notes, tags} = result.data;
+    const doc = await db.collection("waitlist").findOneAndUpdate(
+        // signups are stored lowercased
+        {email: decodeURIComponent(email).toLowerCase()},
+        {
+            $set: {
+                ...(notes !== undefined ? {notes} : {}),
+                ...(tags !== undefined ? {tags: [...new Set(tags)]} : {}),
+            },
+        },
+        {returnDocument: "after", projection: {_id: 0}},
+    );
+
+    if (!doc) {
+        return errorResponse(ErrorCode.NotFound);
+    }
+    return NextResponse.json(doc);
+}
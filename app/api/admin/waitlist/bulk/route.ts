@@ -0,0 +1,99 @@
+import db, {client} from "@/shared/lib/mongodb";
+import {requireAdmin} from "@/shared/lib/admin";
+import {ErrorCode, errorResponse} from "@/shared/lib/errors";
+import {buildSearchFilter} from "@/shared/lib/waitlist";
+
+import {z} from 'zod';
+import {Filter} from "mongodb";
+import {NextRequest, NextResponse} from "next/server";
+
+const Tags = z.array(z.string().trim().min(1).max(50)).max(50);
+
+// Targets either an explicit list of emails or the same filters as the search endpoint
+const BulkSchema = z.object({
+    emails: z.array(z.string().max(320)).min(1).max(10000).optional(),
+    filter: z.object({
+        q: z.string().optional(),
+        domain: z.string().optional(),
+        from: z.string().optional(),
+        to: z.string().optional(),
+    }).refine(filter => Object.values(filter).some(Boolean), "filter must not be empty").optional(),
+    addTags: Tags.optional(),
+    removeTags: Tags.optional(),
+}).refine(body => Boolean(body.emails) !== Boolean(body.filter), "Provide either emails or filter")
+    .refine(body => Boolean(body.addTags?.length || body.removeTags?.length), "Provide addTags or removeTags");
+
+export async function POST(request: NextRequest) {
+    const denied = await requireAdmin();
+    if (denied) {
+        return denied;
+    }
+
+    const result = BulkSchema.safeParse(await request.json().catch(() => null));
+    if (!result.success) {
+        return errorResponse(ErrorCode.InvalidRequest, result.error.issues[0]?.message);
+    }
+
+    const {addTags = [], removeTags = []} = result.data;
+    // signups are stored lowercased
+    const emails = result.data.emails?.map(email => email.trim().toLowerCase());
+    const filter: Filter<any> | null = emails
+        ? {email: {$in: emails}}
+        : buildSearchFilter(result.data.filter!);
+    if (!filter) {
+        return errorResponse(ErrorCode.InvalidRequest, "Invalid date range");
+    }
+
+    // only entries missing an added tag or carrying a removed one are written,
+    // so modified counts real changes
+    const needsChange: Filter<any> = {
+        $or: [
+            ...(removeTags.length ? [{tags: {$in: removeTags}}] : []),
+            ...(addTags.length ? [{tags: {$not: {$all: addTags}}}] : []),
+        ],
+    };
+
+    const waitlist = db.collection("waitlist");
+    const session = client.startSession();
+    try {
+        return await session.withTransaction(async () => {
+            const before = emails
+                ? await waitlist.find(filter, {projection: {email: 1}, session}).toArray()
+                : null;
+            const changing = emails
+                ? await waitlist.find({$and: [filter, needsChange]}, {projection: {email: 1}, session}).toArray()
+                : null;
+            const matched = before?.length ?? await waitlist.countDocuments(filter, {session});
+
+            // one pipeline update applies removals and additions together;
+            // $literal keeps tags starting with "$" from being read as field paths
+            const {modifiedCount} = await waitlist.updateMany({$and: [filter, needsChange]}, [{
+                $set: {
+                    tags: {
+                        $setUnion: [
+                            {$setDifference: [{$ifNull: ["$tags", []]}, {$literal: removeTags}]},
+                            {$literal: addTags},
+                        ],
+                    },
+                },
+            }], {session});
+
+            if (!emails) {
+                return NextResponse.json({matched, modified: modifiedCount});
+            }
+
+            const found = new Set(before!.map(doc => doc.email));
+            const changed = new Set(changing!.map(doc => doc.email));
+            return NextResponse.json({
+                matched,
+                modified: modifiedCount,
+                results: emails.map(email => ({
+                    email,
+                    status: changed.has(email) ? "updated" : found.has(email) ? "unchanged" : "not_found",
+                })),
+            });
+        });
+    } finally {
+        await session.endSession();
+    }
+}
@@ -0,0 +1,84 @@
+import db, {client} from "@/shared/lib/mongodb";
+import {requireAdmin} from "@/shared/lib/admin";
+import {ErrorCode, errorResponse} from "@/shared/lib/errors";
+
+import {z} from 'zod';
+import {ObjectId} from "mongodb";
+import {NextRequest, NextResponse} from "next/server";
+
+const RestoreSchema = z.object({
+    ids: z.array(z.string().refine(ObjectId.isValid, "Invalid id")).min(1).max(1000),
+});
+
+// Entries moved out by merges, newest first, until they're purged
+export async function GET(request: NextRequest) {
+    const denied = await requireAdmin();
+    if (denied) {
+        return denied;
+    }
+
+    const params = request.nextUrl.searchParams;
+    const page = Math.max(Number(params.get("page")) || 1, 1);
+    const limit = Math.min(Math.max(Number(params.get("limit")) || 25, 1), 100);
+
+    const deleted = db.collection("waitlist_deleted");
+    const [docs, totalDocs] = await Promise.all([
+        deleted.find()
+            .sort({deletedAt: -1})
+            .skip((page - 1) * limit)
+            .limit(limit)
+            .toArray(),
+        deleted.countDocuments(),
+    ]);
+
+    return NextResponse.json({
+        docs: docs.map(doc => ({...doc, createdAt: doc._id.getTimestamp()})),
+        totalDocs,
+        page,
+        limit,
+        totalPages: Math.ceil(totalDocs / limit),
+    });
+}
+
+// {"ids": [...]} moves entries back onto the waitlist with their original signup time
+export async function POST(request: NextRequest) {
+    const denied = await requireAdmin();
+    if (denied) {
+        return denied;
+    }
+
+    const result = RestoreSchema.safeParse(await request.json().catch(() => null));
+    if (!result.success) {
+        return errorResponse(ErrorCode.InvalidRequest, result.error.issues[0]?.message);
+    }
+
+    const ids = result.data.ids.map(id => new ObjectId(id));
+    const session = client.startSession();
+    try {
+        const restored = await session.withTransaction(async () => {
+            const docs = await db.collection("waitlist_deleted")
+                .find({_id: {$in: ids}}, {session}).toArray();
+            if (docs.length > 0) {
+                await db.collection("waitlist").insertMany(
+                    docs.map(doc => Object.fromEntries(Object.entries(doc)
+                        .filter(([field]) => field !== "deletedAt" && field !== "mergedInto"))), {session},
+                );
+                await db.collection("waitlist_deleted").deleteMany({_id: {$in: ids}}, {session});
+            }
+            return docs.map(doc => doc._id.toHexString());
+        });
+
+        return NextResponse.json({
+            restored,
+            notFound: ids.map(id => id.toHexString()).filter(id => !restored.includes(id)),
+        });
+    } catch (err: any) {
+        if (err.code === 11000) {
+            return errorResponse(ErrorCode.AlreadySubscribed,
+                "An entry with the same email is on the waitlist; merge or delete it first");
+        }
+        throw err;
+    } finally {
+        await session.endSession();
+    }
+}
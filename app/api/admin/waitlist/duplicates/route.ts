@@ -0,0 +1,104 @@
+import db, {client} from "@/shared/lib/mongodb";
+import {requireAdmin} from "@/shared/lib/admin";
+import {normalizeEmail} from "@/shared/lib/email";
+import {reportError} from "@/shared/lib/reportError";
+import {moveToDeleted} from "@/shared/lib/waitlist";
+
+import {Document, WithId} from "mongodb";
+import {NextResponse} from "next/server";
+
+// fields taken from the earliest entry, or the first later one that has them
+const FIRST_VALUE_FIELDS = [
+    "emailScore", "bucket", "invite", "source", "utmSource", "utmMedium",
+    "utmCampaign", "country", "region",
+];
+
+// Groups of entries sharing a normalized email, each sorted by signup time.
+// Entries from before normalization fall back to their lowercased address, so
+// legacy Gmail aliases of each other aren't grouped
+async function findDuplicates() {
+    const groups = await db.collection("waitlist").aggregate<{ _id: string, entries: WithId<Document>[] }>([
+        {$sort: {_id: 1}},
+        {
+            $project: {
+                email: 1, tags: 1, notes: 1, requestedAccess: 1,
+                ...Object.fromEntries(FIRST_VALUE_FIELDS.map(field => [field, 1])),
+                key: {$ifNull: ["$normalizedEmail", {$toLower: "$email"}]},
+            },
+        },
+        {$group: {_id: "$key", entries: {$push: "$$ROOT"}, count: {$sum: 1}}},
+        {$match: {count: {$gt: 1}}},
+        {$sort: {_id: 1}},
+    ], {allowDiskUse: true}).toArray();
+    return groups.map(({_id, entries}) => [_id, entries] as [string, WithId<Document>[]]);
+}
+
+function merge([kept, ...duplicates]: WithId<Document>[]) {
+    const entries = [kept, ...duplicates];
+    const merged: Document = {
+        email: kept.email.toLowerCase(),
+        normalizedEmail: normalizeEmail(kept.email),
+        tags: [...new Set(entries.flatMap(doc => doc.tags ?? []))],
+        notes: entries.map(doc => doc.notes).filter(Boolean).join("\n\n"),
+        requestedAccess: entries.every(doc => doc.requestedAccess ?? false),
+    };
+    for (const field of FIRST_VALUE_FIELDS) {
+        merged[field] = entries.find(doc => doc[field] != null)?.[field] ?? null;
+    }
+    return merged;
+}
+
+const describe = (groups: [string, WithId<Document>[]][]) =>
+    groups.map(([, [kept, ...duplicates]]) => ({
+        email: kept.email,
+        createdAt: kept._id.getTimestamp().toISOString(),
+        duplicates: duplicates.map(doc => doc.email),
+    }));
+
+// Lists what POST would merge
+export async function GET() {
+    const denied = await requireAdmin();
+    if (denied) {
+        return denied;
+    }
+    return NextResponse.json({groups: describe(await findDuplicates())});
+}
+
+// Folds each group into its earliest entry, keeping that entry's signup date.
+// The other entries can be restored through /api/admin/waitlist/deleted
+export async function POST() {
+    const denied = await requireAdmin();
+    if (denied) {
+        return denied;
+    }
+
+    const groups = await findDuplicates();
+    const waitlist = db.collection("waitlist");
+    const merged: typeof groups = [];
+    const failed: typeof groups = [];
+    for (const entry of groups) {
+        const [kept, ...duplicates] = entry[1];
+        // each group merges completely or not at all, e.g. when the merged
+        // address collides with an entry outside the group
+        const session = client.startSession();
+        try {
+            await session.withTransaction(async () => {
+                // move the duplicates out first so the unique email indexes allow the update
+                await moveToDeleted(duplicates.map(doc => doc._id), {mergedInto: kept._id}, session);
+                await waitlist.updateOne({_id: kept._id}, {$set: merge(entry[1])}, {session});
+            });
+            merged.push(entry);
+        } catch (err) {
+            await reportError(err, {tags: {route: "duplicates", step: "merge"}});
+            failed.push(entry);
+        } finally {
+            await session.endSession();
+        }
+    }
+
+    return NextResponse.json({
+        merged: merged.length,
+        groups: describe(merged),
+        failed: describe(failed),
+    });
+}
@@ -0,0 +1,39 @@
+import {requireAdmin} from "@/shared/lib/admin";
+import {ErrorCode, errorResponse} from "@/shared/lib/errors";
+import {getWaitlistOverride, isWaitlistOpen, setWaitlistOverride} from "@/shared/lib/waitlist";
+
+import {z} from 'zod';
+import {NextRequest, NextResponse} from "next/server";
+
+const OverrideSchema = z.object({
+    override: z.enum(["open", "closed"]).nullable(),
+});
+
+async function state() {
+    const [override, open] = await Promise.all([getWaitlistOverride(), isWaitlistOpen()]);
+    return NextResponse.json({override, open});
+}
+
+export async function GET() {
+    const denied = await requireAdmin();
+    if (denied) {
+        return denied;
+    }
+    return await state();
+}
+
+// {"override": "open"} reopens a full or closed list, null restores the configured window and cap
+export async function PUT(request: NextRequest) {
+    const denied = await requireAdmin();
+    if (denied) {
+        return denied;
+    }
+
+    const result = OverrideSchema.safeParse(await request.json().catch(() => null));
+    if (!result.success) {
+        return errorResponse(ErrorCode.InvalidRequest, "override must be \"open\", \"closed\" or null");
+    }
+
+    await setWaitlistOverride(result.data.override);
+    return await state();
+}
@@ -0,0 +1,48 @@
+import db from "@/shared/lib/mongodb";
+import {requireAdmin} from "@/shared/lib/admin";
+import {ErrorCode, errorResponse} from "@/shared/lib/errors";
+import {buildSearchFilter} from "@/shared/lib/waitlist";
+
+import {NextRequest, NextResponse} from "next/server";
+
+export async function GET(request: NextRequest) {
+    const denied = await requireAdmin();
+    if (denied) {
+        return denied;
+    }
+
+    const params = request.nextUrl.searchParams;
+    const page = Math.max(Number(params.get("page")) || 1, 1);
+    const limit = Math.min(Math.max(Number(params.get("limit")) || 25, 1), 100);
+
+    const filter = buildSearchFilter({
+        q: params.get("q"),
+        domain: params.get("domain"),
+        from: params.get("from"),
+        to: params.get("to"),
+    });
+    if (!filter) {
+        return errorResponse(ErrorCode.InvalidRequest, "Invalid date range");
+    }
+
+    const waitlist = db.collection("waitlist");
+    const [docs, totalDocs] = await Promise.all([
+        waitlist.find(filter)
+            .sort({_id: 1})
+            .skip((page - 1) * limit)
+            .limit(limit)
+            .toArray(),
+        waitlist.countDocuments(filter),
+    ]);
+
+    return NextResponse.json({
+        docs: docs.map(({_id, ...doc}) => ({
+            ...doc,
+            createdAt: _id.getTimestamp().toISOString(),
+        })),
+        totalDocs,
+        page,
+        limit,
+        totalPages: Math.ceil(totalDocs / limit),
+    });
+}
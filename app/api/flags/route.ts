@@ -0,0 +1,13 @@
+import {getFlags} from "@/shared/lib/flags";
+
+import {NextResponse} from "next/server";
+
+export const dynamic = "force-dynamic";
+
+export async function GET() {
+    return NextResponse.json(await getFlags(), {
+        headers: {
+            "Cache-Control": "no-store",
+        },
+    });
+}
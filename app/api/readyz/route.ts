@@ -0,0 +1,24 @@
+import db from "@/shared/lib/mongodb";
+
+import {NextResponse} from "next/server";
+
+export const dynamic = "force-dynamic";
+
+// fail fast instead of waiting out the driver's server selection timeout
+const PING_TIMEOUT_MS = 2000;
+
+export async function GET() {
+    const ready = await Promise.race([
+        db.command({ping: 1}).then(() => true),
+        new Promise<boolean>(resolve => setTimeout(() => resolve(false), PING_TIMEOUT_MS)),
+    ]).catch(() => false);
+
+    return NextResponse.json({
+        status: ready ? "ok" : "unavailable",
+    }, {
+        status: ready ? 200 : 503,
+        headers: {
+            "Cache-Control": "no-store",
+        },
+    });
+}
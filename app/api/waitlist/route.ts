@@ -1,19 +1,77 @@
 import db from "@/shared/lib/mongodb";
+import {requireAdmin} from "@/shared/lib/admin";
+import {ErrorCode, errorResponse} from "@/shared/lib/errors";
+import {encryptedZip} from "@/shared/lib/zip";
 
-import {NextResponse} from "next/server";
+import {z} from 'zod';
+import {NextRequest, NextResponse} from "next/server";
 import {stringify} from "csv-stringify/sync";
 
-export async function GET() {
+const EncryptedExportSchema = z.object({
+    passphrase: z.string().min(12).max(256),
+});
+
+async function exportCsv() {
     const docs = await (db.collection("waitlist").find().toArray());
-    const waitlist = docs.map(doc => ({Email: doc.email!}));
+    const waitlist = docs.map(doc => ({
+        "Email": doc.email!,
+        "Email Score": doc.emailScore,
+        "Source": doc.source,
+        "UTM Source": doc.utmSource,
+        "UTM Medium": doc.utmMedium,
+        "UTM Campaign": doc.utmCampaign,
+        "Bucket": doc.bucket,
+        "Country": doc.country,
+        "Region": doc.region,
+        "Access Requested": doc.requestedAccess,
+        "Invite": doc.invite,
+        "Tags": doc.tags?.join(";"),
+        "Notes": doc.notes,
+    }));
 
-    return new NextResponse(stringify(waitlist, {
+    return stringify(waitlist, {
         header: true,
-        columns: ["Email"],
-    }), {
+        columns: [
+            "Email", "Email Score", "Source", "UTM Source", "UTM Medium", "UTM Campaign",
+            "Bucket", "Country", "Region", "Access Requested", "Invite",
+            "Tags", "Notes",
+        ],
+    });
+}
+
+export async function GET() {
+    const denied = await requireAdmin();
+    if (denied) {
+        return denied;
+    }
+
+    return new NextResponse(await exportCsv(), {
         headers: {
             "Content-Type": "text/csv",
             "Content-Disposition": "attachment; filename=waitlist.csv",
         },
     });
-}
\ No newline at end of file
+}
+// {"passphrase": "..."} returns the export as an AES-256 encrypted zip. The
+// passphrase is chosen by the admin and should reach recipients separately
+// from the file
+export async function POST(request: NextRequest) {
+    const denied = await requireAdmin();
+    if (denied) {
+        return denied;
+    }
+
+    const result = EncryptedExportSchema.safeParse(await request.json().catch(() => null));
+    if (!result.success) {
+        return errorResponse(ErrorCode.InvalidRequest, "passphrase must be 12 to 256 characters");
+    }
+
+    const archive = encryptedZip("waitlist.csv", Buffer.from(await exportCsv()), result.data.passphrase);
+    return new NextResponse(new Uint8Array(archive), {
+        headers: {
+            "Content-Type": "application/zip",
+            "Content-Disposition": "attachment; filename=waitlist.zip",
+            "Cache-Control": "no-store",
+        },
+    });
+}
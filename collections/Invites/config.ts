@@ -0,0 +1,66 @@
+import {isAdmin} from "@/collections/common";
+
+import {CollectionConfig} from "payload";
+import {randomBytes} from "crypto";
+
+// Minted and revoked by admins through the Payload admin panel or REST API,
+// checked by addToWaitlist when WAITLIST_REQUIRE_INVITE is set
+const Invites: CollectionConfig = {
+    slug: "invites",
+    access: {
+        create: isAdmin,
+        read: isAdmin,
+        update: isAdmin,
+        delete: isAdmin,
+    },
+    admin: {
+        useAsTitle: "code",
+    },
+    fields: [
+        {
+            name: "code",
+            type: "text",
+            required: true,
+            unique: true,
+            index: true,
+            defaultValue: () => randomBytes(5).toString("hex").toUpperCase(),
+            hooks: {
+                // codes are matched case-insensitively at signup
+                beforeValidate: [
+                    ({value}) => typeof value === "string" ? value.trim().toUpperCase() : value,
+                ],
+            },
+        },
+        {
+            name: "maxUses",
+            type: "number",
+            required: true,
+            min: 1,
+            defaultValue: 1,
+        },
+        {
+            name: "uses",
+            type: "number",
+            defaultValue: 0,
+            admin: {
+                readOnly: true,
+            },
+        },
+        {
+            name: "expiresAt",
+            type: "date",
+            admin: {
+                date: {
+                    pickerAppearance: "dayAndTime",
+                },
+            },
+        },
+        {
+            name: "revoked",
+            type: "checkbox",
+            defaultValue: false,
+        },
+    ],
+};
+
+export default Invites;
@@ -0,0 +1,19 @@
+import {reportError} from "@/shared/lib/reportError";
+
+import type {Instrumentation} from "next";
+
+// Next calls this for uncaught errors in route handlers, server actions,
+// server components and middleware
+export const onRequestError: Instrumentation.onRequestError = async (error, request, context) => {
+    await reportError(error, {
+        tags: {
+            routerKind: context.routerKind,
+            routePath: context.routePath,
+            routeType: context.routeType,
+        },
+        request: {
+            url: request.path,
+            method: request.method,
+        },
+    });
+};
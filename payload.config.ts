@@ -2,6 +2,7 @@ import Users from "./collections/Users/config";
 import Media from "./collections/Media/config";
 import Accounts from "./collections/Accounts/config";
 import Transactions from "./collections/Transactions/config";
+import Invites from "./collections/Invites/config";
 
 import sharp from "sharp";
 import {lexicalEditor} from "@payloadcms/richtext-lexical";
@@ -36,7 +37,7 @@ export default buildConfig({
 
     // Define and configure your collections in this array
     collections: [
-        Users, Media, Accounts, Transactions,
+        Users, Media, Accounts, Transactions, Invites,
     ],
 
     // Your Payload secret - should be a complex and secure string, unguessable
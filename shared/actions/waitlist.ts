@@ -1,58 +1,220 @@
 "use server";
 
 import db from "@/shared/lib/mongodb";
+import payload from "@/shared/lib/payload";
+import env from "@/shared/lib/env";
+import {getDomain, isAllowed, minEmailScore, normalizeEmail, scoreEmail} from "@/shared/lib/email";
+import {ErrorCode, errorMessage} from "@/shared/lib/errors";
+import {isEnabled} from "@/shared/lib/flags";
+import {reportError} from "@/shared/lib/reportError";
+import {appendRow, isSheetSyncEnabled} from "@/shared/lib/sheets";
+import {isWaitlistOpen} from "@/shared/lib/waitlist";
 import {ActionState} from "@/shared/lib/types";
+import type {MongooseAdapter} from "@payloadcms/db-mongodb";
 
 import {z} from 'zod';
+import {createHash} from "crypto";
+import {headers} from "next/headers";
+import {after} from "next/server";
+import {track} from "@vercel/analytics/server";
 
 const WaitlistedCustomerSchema = z.object({
     email: z.email(),
+    source: z.string().max(100).optional(),
+    utm_source: z.string().max(100).optional(),
+    utm_medium: z.string().max(100).optional(),
+    utm_campaign: z.string().max(100).optional(),
+    invite: z.string().trim().max(64).optional(),
 });
-type WaitlistFormState = ActionState<{ email: string }>;
+type WaitlistFormState = ActionState<{
+    email: string,
+    invite?: string,
+    bucket?: string,
+    requestedAccess?: boolean,
+}>;
+
+// the same email always lands in the same bucket
+function getExperimentBucket(email: string): string | undefined {
+    const buckets = env.waitlist.experimentBuckets;
+    if (buckets.length === 0) {
+        return undefined;
+    }
+
+    const hash = createHash("sha256").update(email.toLowerCase()).digest();
+    return buckets[hash.readUInt32BE(0) % buckets.length];
+}
+
+// Populated by Vercel's edge network. Elsewhere any client could send these
+// headers, so they're ignored
+async function getLocation() {
+    if (!env.vercel) {
+        return {country: null, region: null};
+    }
+
+    const requestHeaders = await headers();
+    return {
+        country: requestHeaders.get("x-vercel-ip-country"),
+        region: requestHeaders.get("x-vercel-ip-country-region"),
+    };
+}
+
+// custom events are a paid Vercel Analytics feature; "signup-events" is on by
+// default and can be switched off through FEATURE_FLAGS or /api/admin/flags
+async function trackEvent(name: string, properties: Record<string, string | null>) {
+    // analytics must never fail a signup that was already stored
+    try {
+        if (await isEnabled("signup-events")) {
+            await track(name, properties);
+        }
+    } catch (err) {
+        console.error(err);
+    }
+}
+
+// Rejected and hidden-duplicate signups are kept for 90 days for funnel
+// diagnostics. Only the domain is stored; an unkeyed hash of the address could
+// be reversed by hashing candidate emails
+async function recordAttempt(input: string, reason: ErrorCode) {
+    input = input.trim().toLowerCase();
+    await Promise.all([
+        db.collection("waitlist_attempts").insertOne({
+            domain: input.includes("@") ? getDomain(input).slice(0, 255) : null,
+            reason: reason,
+            createdAt: new Date(),
+        }),
+        trackEvent("Waitlist Rejected", {reason}),
+    ]);
+}
+
+// Invites live in Payload's database, which may differ from the waitlist's.
+// Uses are claimed with a single conditional update so concurrent signups
+// can't redeem the same code more than maxUses times
+const invites = () => (payload.db as MongooseAdapter).collections.invites;
+
+async function claimInvite(code?: string) {
+    if (!code) {
+        return null;
+    }
+
+    return await invites().findOneAndUpdate({
+        code: code.toUpperCase(),
+        revoked: {$ne: true},
+        $expr: {$lt: [{$ifNull: ["$uses", 0]}, "$maxUses"]},
+        $or: [{expiresAt: null}, {expiresAt: {$gt: new Date()}}],
+    }, {$inc: {uses: 1}}, {new: true}).lean<{ _id: unknown, code: string }>();
+}
+
+async function releaseInvite(invite: { _id: unknown }) {
+    await invites().updateOne({_id: invite._id}, {$inc: {uses: -1}});
+}
 
 export async function addToWaitlist(_prev: WaitlistFormState, formData: FormData)
     : Promise<WaitlistFormState> {
-    const result = WaitlistedCustomerSchema.safeParse({
-        email: formData.get("email"),
-    });
-
-    if (!result.success) {
+    const rejected = async (code: ErrorCode): Promise<WaitlistFormState> => {
+        await recordAttempt(String(formData.get("email") ?? ""), code).catch(err => reportError(err, {
+            tags: {action: "addToWaitlist", step: "recordAttempt"},
+        }));
         return {
             success: false,
-            message: "Please provide a valid email address",
+            message: errorMessage(code),
+            code: code,
             data: {
                 email: formData.get("email")! as string,
-            }
+                invite: (formData.get("invite") as string | null) ?? undefined,
+            },
         };
+    };
+
+    const result = WaitlistedCustomerSchema.safeParse({
+        email: formData.get("email"),
+        source: formData.get("source") ?? undefined,
+        utm_source: formData.get("utm_source") ?? undefined,
+        utm_medium: formData.get("utm_medium") ?? undefined,
+        utm_campaign: formData.get("utm_campaign") ?? undefined,
+        invite: formData.get("invite") ?? undefined,
+    });
+
+    if (!result.success) {
+        return await rejected(ErrorCode.InvalidEmail);
+    }
+
+    if (!await isWaitlistOpen()) {
+        return await rejected(ErrorCode.ListClosed);
+    }
+
+    const email = result.data.email.toLowerCase();
+    const emailScore = await scoreEmail(email);
+    if (emailScore < minEmailScore) {
+        return await rejected(ErrorCode.UndeliverableEmail);
     }
 
-    const email = result.data.email;
+    const bucket = getExperimentBucket(email);
+    const requestedAccess = !isAllowed(email);
     const waitlistedCustomers = db.collection("waitlist");
+    const subscribed: WaitlistFormState = {
+        success: true,
+        message: requestedAccess
+            ? "Access is currently limited. We have received your request and will be in touch."
+            : "Subscribed successfully! You will receive an confirmation email from us soon.",
+        data: {
+            email: "",
+            bucket: bucket,
+            requestedAccess: requestedAccess,
+        },
+    };
+
+    const invite = env.waitlist.requireInvite
+        ? await claimInvite(result.data.invite)
+        : null;
+    if (env.waitlist.requireInvite && !invite) {
+        return await rejected(ErrorCode.InvalidInvite);
+    }
 
     // todo: use Next.js' error handling pattern instead
     try {
-        await waitlistedCustomers.insertOne({email: email});
-        return {
-            success: true,
-            message: "Subscribed successfully! You will receive an confirmation email from us soon.",
-            data: {
-                email: "",
-            },
-        };
+        await waitlistedCustomers.insertOne({
+            email: email,
+            normalizedEmail: normalizeEmail(email),
+            emailScore: emailScore,
+            bucket: bucket ?? null,
+            requestedAccess: requestedAccess,
+            invite: invite?.code ?? null,
+            source: result.data.source ?? null,
+            utmSource: result.data.utm_source ?? null,
+            utmMedium: result.data.utm_medium ?? null,
+            utmCampaign: result.data.utm_campaign ?? null,
+            ...await getLocation(),
+        });
+        await trackEvent("Waitlist Signup", {bucket: bucket ?? null});
+        if (isSheetSyncEnabled()) {
+            // runs after the response is sent so a slow sheet doesn't hold up the form
+            after(() => appendRow([
+                new Date().toISOString(),
+                email,
+                result.data.source ?? null,
+                result.data.utm_source ?? null,
+                result.data.utm_medium ?? null,
+                result.data.utm_campaign ?? null,
+                bucket ?? null,
+                requestedAccess,
+            ]).catch(err => reportError(err, {tags: {action: "addToWaitlist", step: "appendRow"}})));
+        }
+        return subscribed;
     } catch (err: any) {
-        const state = {
-            success: false,
-            message: "Already subscribed",
-            data: {
-                email: formData.get("email")! as string,
-            },
-        };
+        if (invite) {
+            await releaseInvite(invite).catch(releaseErr => reportError(releaseErr, {
+                tags: {action: "addToWaitlist", step: "releaseInvite"},
+            }));
+        }
 
-        if (err.code === 11000) {
-            return {...state, message: "Already subscribed"};
-        } else {
-            console.error(err);
-            return {...state, message: "Internal server error"};
+        const code = err.code === 11000 ? ErrorCode.AlreadySubscribed : ErrorCode.Storage;
+        if (code === ErrorCode.Storage) {
+            await reportError(err, {tags: {action: "addToWaitlist", step: "insert"}});
         }
+
+        const state = await rejected(code);
+        return code === ErrorCode.AlreadySubscribed && env.waitlist.privacyMode
+            ? subscribed
+            : state;
     }
 }
\ No newline at end of file
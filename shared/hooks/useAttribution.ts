@@ -0,0 +1,52 @@
+import {useEffect, useState} from "react";
+
+const UTM_PARAMS = ["utm_source", "utm_medium", "utm_campaign"] as const;
+const STORAGE_KEY = "glix:attribution";
+
+export type Attribution = Partial<Record<typeof UTM_PARAMS[number], string>>;
+
+// Keeps the utm_* parameters of the page a visitor landed on for the rest of
+// the session, so they survive navigating to another page before signing up
+export function captureAttribution() {
+    const params = new URLSearchParams(window.location.search);
+    const attribution: Attribution = {};
+    for (const name of UTM_PARAMS) {
+        const value = params.get(name);
+        if (value) {
+            attribution[name] = value;
+        }
+    }
+
+    if (Object.keys(attribution).length > 0) {
+        sessionStorage.setItem(STORAGE_KEY, JSON.stringify(attribution));
+        // forms on the landing page mount before the layout captures
+        window.dispatchEvent(new Event(STORAGE_KEY));
+    }
+}
+
+// only known keys with string values, whatever ends up in storage
+function readAttribution(): Attribution {
+    try {
+        const stored = JSON.parse(sessionStorage.getItem(STORAGE_KEY) ?? "{}");
+        return Object.fromEntries(UTM_PARAMS
+            .map(name => [name, stored?.[name]])
+            .filter(([, value]) => typeof value === "string"));
+    } catch {
+        sessionStorage.removeItem(STORAGE_KEY);
+        return {};
+    }
+}
+
+// Reads what AttributionCapture in the client layout stored
+export function useAttribution(): Attribution {
+    const [attribution, setAttribution] = useState<Attribution>({});
+
+    useEffect(() => {
+        const update = () => setAttribution(readAttribution());
+        update();
+        window.addEventListener(STORAGE_KEY, update);
+        return () => window.removeEventListener(STORAGE_KEY, update);
+    }, []);
+
+    return attribution;
+}
@@ -0,0 +1,17 @@
+import {auth} from "@/shared/actions/auth";
+import {checkRole} from "@/collections/common";
+import {ErrorCode, errorResponse} from "@/shared/lib/errors";
+
+// Route handlers under /api/admin are restricted to Payload admins. Resolves
+// to the error response to return, 401 without a session and 403 for other
+// roles, or null when the request may proceed
+export async function requireAdmin() {
+    const {user} = await auth();
+    if (!user) {
+        return errorResponse(ErrorCode.Unauthorized);
+    }
+    if (!checkRole(["admin"], user)) {
+        return errorResponse(ErrorCode.Forbidden);
+    }
+    return null;
+}
@@ -0,0 +1,145 @@
+import env from "@/shared/lib/env";
+
+import {Resolver} from "dns/promises";
+
+const FREE_EMAIL_PROVIDERS = new Set([
+    "gmail.com", "googlemail.com", "yahoo.com", "ymail.com", "outlook.com",
+    "hotmail.com", "live.com", "msn.com", "icloud.com", "me.com", "mac.com",
+    "aol.com", "proton.me", "protonmail.com", "gmx.com", "gmx.de", "mail.com",
+    "yandex.com", "yandex.ru", "zoho.com", "qq.com", "163.com", "126.com",
+]);
+
+const DISPOSABLE_EMAIL_PROVIDERS = new Set([
+    "mailinator.com", "guerrillamail.com", "10minutemail.com", "temp-mail.org",
+    "tempmail.com", "yopmail.com", "trashmail.com", "sharklasers.com",
+    "getnada.com", "dispostable.com", "maildrop.cc", "throwawaymail.com",
+]);
+
+export function getDomain(email: string): string {
+    return email.slice(email.lastIndexOf("@") + 1).toLowerCase();
+}
+
+const GMAIL_DOMAINS = new Set(["gmail.com", "googlemail.com"]);
+
+// Identity used to detect duplicates: case-insensitive, and for Gmail also
+// ignoring dots and +suffixes in the local part
+export function normalizeEmail(email: string): string {
+    email = email.toLowerCase();
+    const domain = getDomain(email);
+    if (!GMAIL_DOMAINS.has(domain)) {
+        return email;
+    }
+
+    const local = email.slice(0, email.lastIndexOf("@")).split("+")[0].replaceAll(".", "");
+    return `${local}@gmail.com`;
+}
+
+export function isFreeProvider(domain: string): boolean {
+    return FREE_EMAIL_PROVIDERS.has(domain.toLowerCase());
+}
+
+export function isDisposable(domain: string): boolean {
+    domain = domain.toLowerCase();
+    return DISPOSABLE_EMAIL_PROVIDERS.has(domain) ||
+        env.waitlist.disposableDomains.includes(domain);
+}
+
+// true when no allowlist is configured
+export function isAllowed(email: string): boolean {
+    const {allowedDomains, allowedEmails} = env.waitlist;
+    if (allowedDomains.length === 0 && allowedEmails.length === 0) {
+        return true;
+    }
+    return allowedEmails.includes(email.toLowerCase()) ||
+        allowedDomains.includes(getDomain(email));
+}
+
+// bounded so a slow DNS server can't stall signups
+const resolver = new Resolver({timeout: 2000, tries: 1});
+
+const isMissing = (err: any) => err.code === "ENOTFOUND" || err.code === "ENODATA";
+
+// only a definitive "no such domain/record" counts against the address,
+// lookup timeouts and server failures are treated as deliverable
+async function hasRecords(lookup: () => Promise<unknown[]>): Promise<boolean> {
+    try {
+        return (await lookup()).length > 0;
+    } catch (err: any) {
+        return !isMissing(err);
+    }
+}
+
+async function hasMailServer(domain: string): Promise<boolean> {
+    try {
+        const records = await resolver.resolveMx(domain);
+        // a null MX (RFC 7505) resolves to an empty exchange: the domain takes no mail
+        return records.some(record => record.exchange !== "");
+    } catch (err: any) {
+        if (!isMissing(err)) {
+            return true;
+        }
+        if (err.code === "ENOTFOUND") {
+            return false;
+        }
+    }
+
+    // without MX records the domain's own A/AAAA address is its mail server
+    // (implicit MX, RFC 5321 section 5.1)
+    const [ipv4, ipv6] = await Promise.all([
+        hasRecords(() => resolver.resolve4(domain)),
+        hasRecords(() => resolver.resolve6(domain)),
+    ]);
+    return ipv4 || ipv6;
+}
+
+// Lookups are cached per domain so repeat and duplicate signups from the same
+// domain don't wait on DNS again. Pending lookups are shared too
+const MAIL_SERVER_TTL = 10 * 60 * 1000;
+const MAIL_SERVER_CACHE_SIZE = 1000;
+const mailServers = new Map<string, { result: Promise<boolean>, expiresAt: number }>();
+
+function hasMailServerCached(domain: string): Promise<boolean> {
+    const cached = mailServers.get(domain);
+    if (cached && cached.expiresAt > Date.now()) {
+        return cached.result;
+    }
+
+    // maps iterate in insertion order, so the first key is the oldest entry
+    if (mailServers.size >= MAIL_SERVER_CACHE_SIZE) {
+        mailServers.delete(mailServers.keys().next().value!);
+    }
+    const result = hasMailServer(domain);
+    mailServers.set(domain, {result, expiresAt: Date.now() + MAIL_SERVER_TTL});
+    return result;
+}
+
+// a malformed threshold is logged and disables rejection, scores are still recorded
+function parseMinScore(value?: string): number {
+    if (!value) {
+        return 0;
+    }
+
+    const score = Number(value);
+    if (isNaN(score) || score < 0 || score > 1) {
+        console.error(`WAITLIST_MIN_EMAIL_SCORE must be a number from 0 to 1: "${value}"`);
+        return 0;
+    }
+    return score;
+}
+
+export const minEmailScore = parseMinScore(env.waitlist.minEmailScore);
+
+// Confidence that an already syntax-checked address can receive mail,
+// from 0 (certainly not) to 1 (no problems found)
+export async function scoreEmail(email: string): Promise<number> {
+    const domain = getDomain(email);
+    let score = 1;
+
+    if (isDisposable(domain)) {
+        score -= 0.5;
+    }
+    if (!await hasMailServerCached(domain)) {
+        score -= 0.5;
+    }
+    return score;
+}
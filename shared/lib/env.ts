@@ -2,6 +2,56 @@ const env = {
     url: {
         server: process.env.NEXT_PUBLIC_SERVER_URL!,
     },
+    // set by Vercel on its builds and functions
+    vercel: Boolean(process.env.VERCEL),
+    // errors are reported to this Sentry-compatible DSN when set, see shared/lib/reportError.ts
+    errorReporting: {
+        dsn: process.env.SENTRY_DSN,
+        environment: process.env.VERCEL_ENV ?? process.env.NODE_ENV,
+        release: process.env.VERCEL_GIT_COMMIT_SHA,
+    },
+    // new signups are appended to this sheet, which must be shared with the service account
+    googleSheets: {
+        spreadsheetId: process.env.GOOGLE_SHEETS_SPREADSHEET_ID,
+        // sheet name or A1 range the rows are appended after
+        range: process.env.GOOGLE_SHEETS_RANGE ?? "Sheet1",
+        clientEmail: process.env.GOOGLE_SERVICE_ACCOUNT_EMAIL,
+        // PEM key from the service account's JSON key file, newlines may be escaped as \n
+        privateKey: process.env.GOOGLE_SERVICE_ACCOUNT_KEY?.replaceAll("\\n", "\n"),
+    },
+    // comma-separated, e.g. "referrals,captcha=false"; a bare name means enabled
+    featureFlags: Object.fromEntries(
+        (process.env.FEATURE_FLAGS ?? "").split(",")
+            .map(entry => entry.split("=").map(part => part.trim()))
+            .filter(([name]) => name)
+            .map(([name, value]) => [name, value === undefined || value === "true" || value === "1"])
+    ) as Record<string, boolean>,
+    waitlist: {
+        // 0 or unset means no cap
+        maxSize: Number(process.env.WAITLIST_MAX_SIZE ?? 0),
+        // ISO 8601 timestamps, e.g. 2026-11-01T00:00:00Z
+        opensAt: process.env.WAITLIST_OPENS_AT,
+        closesAt: process.env.WAITLIST_CLOSES_AT,
+        // signups must carry a valid code from the invites collection
+        requireInvite: process.env.WAITLIST_REQUIRE_INVITE === "true",
+        // answer duplicate signups like new ones so membership can't be probed
+        privacyMode: process.env.WAITLIST_PRIVACY_MODE === "true",
+        // addresses scoring below this are rejected, 0 only flags them
+        // validated in shared/lib/email.ts
+        minEmailScore: process.env.WAITLIST_MIN_EMAIL_SCORE,
+        // comma-separated, in addition to the built-in list
+        disposableDomains: (process.env.WAITLIST_DISPOSABLE_DOMAINS ?? "")
+            .split(",").map(domain => domain.trim().toLowerCase()).filter(Boolean),
+        // when either list is set, only matching emails join the waitlist and
+        // everyone else is recorded as requesting access
+        allowedDomains: (process.env.WAITLIST_ALLOWED_DOMAINS ?? "")
+            .split(",").map(domain => domain.trim().toLowerCase()).filter(Boolean),
+        allowedEmails: (process.env.WAITLIST_ALLOWED_EMAILS ?? "")
+            .split(",").map(email => email.trim().toLowerCase()).filter(Boolean),
+        // comma-separated bucket names, e.g. "control,variant-a"
+        experimentBuckets: (process.env.WAITLIST_EXPERIMENT_BUCKETS ?? "")
+            .split(",").map(bucket => bucket.trim()).filter(Boolean),
+    },
 };
 
-export default env;
\ No newline at end of file
+export default env;
@@ -0,0 +1,35 @@
+import {NextResponse} from "next/server";
+
+// Stable codes clients can branch on instead of matching message strings
+export const ErrorCode = {
+    InvalidEmail: "invalid_email",
+    UndeliverableEmail: "undeliverable_email",
+    AlreadySubscribed: "already_subscribed",
+    ListClosed: "list_closed",
+    InvalidInvite: "invalid_invite",
+    Storage: "storage",
+    InvalidRequest: "invalid_request",
+    Unauthorized: "unauthorized",
+    Forbidden: "forbidden",
+    NotFound: "not_found",
+} as const;
+export type ErrorCode = typeof ErrorCode[keyof typeof ErrorCode];
+
+const errors: Record<ErrorCode, { status: number, message: string }> = {
+    [ErrorCode.InvalidEmail]: {status: 400, message: "Please provide a valid email address"},
+    [ErrorCode.UndeliverableEmail]: {status: 400, message: "Please use an email address that can receive mail"},
+    [ErrorCode.AlreadySubscribed]: {status: 409, message: "Already subscribed"},
+    [ErrorCode.ListClosed]: {status: 403, message: "The waitlist is currently closed"},
+    [ErrorCode.InvalidInvite]: {status: 403, message: "Please provide a valid invite code"},
+    [ErrorCode.Storage]: {status: 500, message: "Internal server error"},
+    [ErrorCode.InvalidRequest]: {status: 400, message: "Invalid request"},
+    [ErrorCode.Unauthorized]: {status: 401, message: "Unauthorized"},
+    [ErrorCode.Forbidden]: {status: 403, message: "Forbidden"},
+    [ErrorCode.NotFound]: {status: 404, message: "Not found"},
+};
+
+export const errorMessage = (code: ErrorCode) => errors[code].message;
+
+export function errorResponse(code: ErrorCode, message = errorMessage(code)) {
+    return NextResponse.json({code, message}, {status: errors[code].status});
+}
@@ -0,0 +1,30 @@
+import db from "@/shared/lib/mongodb";
+import env from "@/shared/lib/env";
+
+export type Flags = Record<string, boolean>;
+
+// flags that were always on before they became toggles
+const defaults: Flags = {
+    "signup-events": true,
+};
+
+// overrides set through /api/admin/flags win over FEATURE_FLAGS, which wins over the defaults
+const settings = () => db.collection<{ _id: string, flags: Flags }>("settings");
+
+export async function getFlags(): Promise<Flags> {
+    const overrides = (await settings().findOne({_id: "flags"}))?.flags ?? {};
+    return {...defaults, ...env.featureFlags, ...overrides};
+}
+
+export async function isEnabled(flag: string): Promise<boolean> {
+    return (await getFlags())[flag] ?? false;
+}
+
+// null drops the override so the environment value applies again
+export async function setFlagOverride(flag: string, value: boolean | null) {
+    await settings().updateOne(
+        {_id: "flags"},
+        value === null ? {$unset: {[`flags.${flag}`]: ""}} : {$set: {[`flags.${flag}`]: value}},
+        {upsert: true},
+    );
+}
@@ -14,6 +14,21 @@ const db = client.db("glix");
 const ensureIndexes = async () => {
     const waitlistedCustomers = db.collection('waitlist');
     await waitlistedCustomers.createIndex({email: 1}, {unique: true});
+    // entries from before normalization have no normalizedEmail
+    await waitlistedCustomers.createIndex({normalizedEmail: 1}, {
+        unique: true,
+        partialFilterExpression: {normalizedEmail: {$exists: true}},
+    });
+
+    // merged or deleted entries stay restorable for 30 days before they're purged
+    await db.collection('waitlist_deleted').createIndex(
+        {deletedAt: 1}, {expireAfterSeconds: 30 * 24 * 60 * 60},
+    );
+
+    // rejected attempts are diagnostics, not records worth keeping forever
+    await db.collection('waitlist_attempts').createIndex(
+        {createdAt: 1}, {expireAfterSeconds: 90 * 24 * 60 * 60},
+    );
 };
 ensureIndexes().catch(console.error);
 
@@ -0,0 +1,101 @@
+import env from "@/shared/lib/env";
+
+import {randomUUID} from "crypto";
+
+// Sends errors to any service that accepts Sentry's envelope protocol
+// (Sentry, GlitchTip, self-hosted Sentry) using just fetch, so no SDK is
+// bundled. Without SENTRY_DSN errors only go to the console.
+// https://develop.sentry.dev/sdk/data-model/envelopes/
+
+export interface ErrorContext {
+    tags?: Record<string, string | undefined>;
+    extra?: Record<string, unknown>;
+    request?: { url: string, method: string };
+}
+
+function parseDsn(dsn: string) {
+    try {
+        // https://<public key>@<host>[/<path>]/<project id>
+        const url = new URL(dsn);
+        const segments = url.pathname.split("/");
+        const project = segments.pop();
+        if (!url.username || !project) {
+            return null;
+        }
+        return {
+            dsn: dsn,
+            key: url.username,
+            endpoint: `${url.protocol}//${url.host}${segments.join("/")}/api/${project}/envelope/`,
+        };
+    } catch {
+        return null;
+    }
+}
+
+const target = env.errorReporting.dsn ? parseDsn(env.errorReporting.dsn) : null;
+if (env.errorReporting.dsn && !target) {
+    console.error(`SENTRY_DSN is not a valid DSN: "${env.errorReporting.dsn}"`);
+}
+
+// V8 frames, e.g. "    at addToWaitlist (/var/task/.next/server/chunks/123.js:1:2345)"
+function parseStack(stack?: string) {
+    const frames = (stack ?? "").split("\n").flatMap(line => {
+        const match = /^\s*at (?:(.+?) \()?(.+?):(\d+):(\d+)\)?$/.exec(line);
+        return match ? [{
+            function: match[1] ?? "?",
+            filename: match[2],
+            lineno: Number(match[3]),
+            colno: Number(match[4]),
+        }] : [];
+    });
+    // sentry lists frames oldest first
+    return frames.length ? {frames: frames.reverse()} : undefined;
+}
+
+export async function reportError(error: unknown, context: ErrorContext = {}) {
+    console.error(error);
+    if (!target) {
+        return;
+    }
+
+    const err = error instanceof Error ? error : new Error(String(error));
+    const eventId = randomUUID().replaceAll("-", "");
+    const event = {
+        event_id: eventId,
+        timestamp: Date.now() / 1000,
+        platform: "node",
+        level: "error",
+        environment: env.errorReporting.environment,
+        release: env.errorReporting.release,
+        exception: {
+            values: [{
+                type: err.name,
+                value: err.message,
+                stacktrace: parseStack(err.stack),
+            }],
+        },
+        tags: context.tags,
+        extra: {
+            ...context.extra,
+            digest: (err as { digest?: string }).digest,
+        },
+        request: context.request,
+    };
+
+    const envelope = [
+        JSON.stringify({event_id: eventId, dsn: target.dsn, sent_at: new Date().toISOString()}),
+        JSON.stringify({type: "event"}),
+        JSON.stringify(event),
+    ].join("\n");
+
+    // reporting must never take the request down with it
+    await fetch(target.endpoint, {
+        method: "POST",
+        headers: {
+            "Content-Type": "application/x-sentry-envelope",
+            "X-Sentry-Auth": `Sentry sentry_version=7, sentry_key=${target.key}, sentry_client=glix/0.1.0`,
+        },
+        body: envelope,
+        signal: AbortSignal.timeout(2000),
+    }).catch(reportErr => console.error("Failed to report error", reportErr));
+}
@@ -0,0 +1,71 @@
+import env from "@/shared/lib/env";
+
+import {createSign} from "crypto";
+
+// Appends signups to a Google Sheet shared with a service account, through the
+// Sheets REST API so the googleapis client isn't needed.
+// https://developers.google.com/identity/protocols/oauth2/service-account#httprest
+
+type Cell = string | number | boolean | null;
+
+let token: { value: string, expiresAt: number } | null = null;
+
+const base64url = (value: string | Buffer) => Buffer.from(value).toString("base64url");
+
+async function getAccessToken(): Promise<string> {
+    if (token && token.expiresAt > Date.now() + 60_000) {
+        return token.value;
+    }
+
+    const {clientEmail, privateKey} = env.googleSheets;
+    const now = Math.floor(Date.now() / 1000);
+    const unsigned = [
+        base64url(JSON.stringify({alg: "RS256", typ: "JWT"})),
+        base64url(JSON.stringify({
+            iss: clientEmail,
+            scope: "https://www.googleapis.com/auth/spreadsheets",
+            aud: "https://oauth2.googleapis.com/token",
+            iat: now,
+            exp: now + 3600,
+        })),
+    ].join(".");
+    const signature = createSign("RSA-SHA256").update(unsigned).sign(privateKey!);
+
+    const response = await fetch("https://oauth2.googleapis.com/token", {
+        method: "POST",
+        body: new URLSearchParams({
+            grant_type: "urn:ietf:params:oauth:grant-type:jwt-bearer",
+            assertion: `${unsigned}.${base64url(signature)}`,
+        }),
+        signal: AbortSignal.timeout(5000),
+    });
+    if (!response.ok) {
+        throw new Error(`Google token request failed: ${response.status} ${await response.text()}`);
+    }
+
+    const {access_token, expires_in} = await response.json();
+    token = {value: access_token, expiresAt: Date.now() + expires_in * 1000};
+    return token.value;
+}
+
+export const isSheetSyncEnabled = () =>
+    Boolean(env.googleSheets.spreadsheetId && env.googleSheets.clientEmail && env.googleSheets.privateKey);
+
+export async function appendRow(row: Cell[]) {
+    const {spreadsheetId, range} = env.googleSheets;
+    const url = `https://sheets.googleapis.com/v4/spreadsheets/${spreadsheetId}/values/`
+        + `${encodeURIComponent(range)}:append?valueInputOption=RAW&insertDataOption=INSERT_ROWS`;
+
+    const response = await fetch(url, {
+        method: "POST",
+        headers: {
+            "Authorization": `Bearer ${await getAccessToken()}`,
+            "Content-Type": "application/json",
+        },
+        body: JSON.stringify({values: [row]}),
+        signal: AbortSignal.timeout(5000),
+    });
+    if (!response.ok) {
+        throw new Error(`Google Sheets append failed: ${response.status} ${await response.text()}`);
+    }
+}
@@ -0,0 +1,12 @@
+import db from "@/shared/lib/mongodb";
+
+// Signup counts per distinct value of a waitlist field, most common first;
+// entries without the field are grouped under null
+export async function countBy(field: string) {
+    const groups = await db.collection("waitlist").aggregate<{ _id: string | null, count: number }>([
+        {$group: {_id: {$ifNull: [`$${field}`, null]}, count: {$sum: 1}}},
+        {$sort: {count: -1, _id: 1}},
+    ]).toArray();
+
+    return groups.map(({_id, count}) => ({value: _id, count}));
+}
@@ -1,5 +1,8 @@
+import type {ErrorCode} from "@/shared/lib/errors";
+
 export interface ActionState<T> {
     success: boolean,
     message: string,
+    code?: ErrorCode,
     data: T,
-}
\ No newline at end of file
+}
@@ -0,0 +1,120 @@
+import db from "@/shared/lib/mongodb";
+import env from "@/shared/lib/env";
+
+import {ClientSession, Document, Filter, ObjectId} from "mongodb";
+
+// Set by admins through /api/admin/waitlist/override; "open" and "closed"
+// take precedence over the configured window and cap
+export type WaitlistOverride = "open" | "closed" | null;
+
+const settings = () => db.collection<{ _id: string, override: WaitlistOverride }>("settings");
+
+export async function getWaitlistOverride(): Promise<WaitlistOverride> {
+    return (await settings().findOne({_id: "waitlist"}))?.override ?? null;
+}
+
+export async function setWaitlistOverride(override: WaitlistOverride) {
+    await settings().updateOne({_id: "waitlist"}, {$set: {override}}, {upsert: true});
+}
+
+// a malformed timestamp closes the waitlist rather than silently ignoring the window
+function parseTime(name: string, value?: string): number | undefined {
+    if (!value) {
+        return undefined;
+    }
+
+    const time = Date.parse(value);
+    if (isNaN(time)) {
+        console.error(`${name} is not a valid ISO 8601 timestamp: "${value}"`);
+        return NaN;
+    }
+    return time;
+}
+
+export async function isWaitlistOpen(): Promise<boolean> {
+    const override = await getWaitlistOverride();
+    if (override) {
+        return override === "open";
+    }
+
+    const {maxSize} = env.waitlist;
+    const opensAt = parseTime("WAITLIST_OPENS_AT", env.waitlist.opensAt);
+    const closesAt = parseTime("WAITLIST_CLOSES_AT", env.waitlist.closesAt);
+    const now = Date.now();
+
+    if (Number.isNaN(opensAt) || Number.isNaN(closesAt)) {
+        return false;
+    }
+    if (opensAt !== undefined && now < opensAt) {
+        return false;
+    }
+    if (closesAt !== undefined && now >= closesAt) {
+        return false;
+    }
+    if (maxSize > 0) {
+        // access requests from outside the allowlists don't take up places
+        const size = await db.collection("waitlist").countDocuments({requestedAccess: {$ne: true}});
+        return size < maxSize;
+    }
+    return true;
+}
+
+export interface SearchParams {
+    q?: string | null,
+    domain?: string | null,
+    from?: string | null,
+    to?: string | null,
+}
+
+const escapeRegex = (value: string) =>
+    value.replace(/[.*+?^${}()|[\]\\]/g, "\\$&");
+
+// waitlist entries have no timestamp field, the ObjectId carries the signup time
+// as unsigned 32-bit seconds, so only 1970 through early 2106 can be expressed
+const toSeconds = (date: string) => Math.floor(Date.parse(date) / 1000);
+const isValidDate = (date: string) => {
+    const seconds = toSeconds(date);
+    return seconds >= 0 && seconds <= 0xFFFFFFFF;
+};
+const objectIdAt = (date: string) => ObjectId.createFromTime(toSeconds(date));
+
+// Shared by the admin search and bulk endpoints; null for an invalid date range
+export function buildSearchFilter({q, domain, from, to}: SearchParams): Filter<any> | null {
+    if ((from && !isValidDate(from)) || (to && !isValidDate(to))) {
+        return null;
+    }
+
+    const filter: Filter<any> = {};
+    const email = [
+        q && {email: {$regex: escapeRegex(q), $options: "i"}},
+        domain && {email: {$regex: `@${escapeRegex(domain)}$`, $options: "i"}},
+    ].filter(Boolean);
+    if (email.length > 0) {
+        filter.$and = email;
+    }
+    if (from || to) {
+        filter._id = {
+            ...(from ? {$gte: objectIdAt(from)} : {}),
+            ...(to ? {$lt: objectIdAt(to)} : {}),
+        };
+    }
+    return filter;
+}
+
+// Entries are never removed outright: they move to waitlist_deleted, where they
+// can be restored until the TTL index purges them. Keeping them out of the
+// waitlist collection frees their address under the unique indexes and keeps
+// them out of every export, search and stat without extra filters
+export async function moveToDeleted(ids: ObjectId[], fields: Document, session: ClientSession) {
+    const docs = await db.collection("waitlist").find({_id: {$in: ids}}, {session}).toArray();
+    if (docs.length === 0) {
+        return 0;
+    }
+
+    const deletedAt = new Date();
+    await db.collection("waitlist_deleted").insertMany(
+        docs.map(doc => ({...doc, ...fields, deletedAt})), {session},
+    );
+    await db.collection("waitlist").deleteMany({_id: {$in: docs.map(doc => doc._id)}}, {session});
+    return docs.length;
+}
@@ -0,0 +1,104 @@
+import {createCipheriv, createHmac, pbkdf2Sync, randomBytes} from "crypto";
+import {deflateRawSync} from "zlib";
+
+// Single-file zip archives encrypted with WinZip's AES-256 scheme (AE-2),
+// which 7-Zip, WinZip, Keka and bsdtar open with the passphrase. macOS'
+// built-in Archive Utility can't.
+// https://www.winzip.com/en/support/aes-encryption/
+
+const KEY_LENGTH = 32;
+const SALT_LENGTH = 16;
+
+// AE-2 counts blocks little-endian from 1, unlike Node's big-endian aes-256-ctr
+function aesCtr(key: Buffer, data: Buffer): Buffer {
+    const blocks = Math.ceil(data.length / 16);
+    const counters = Buffer.alloc(blocks * 16);
+    for (let i = 0; i < blocks; i++) {
+        counters.writeUInt32LE(i + 1, i * 16);
+    }
+
+    const cipher = createCipheriv("aes-256-ecb", key, null).setAutoPadding(false);
+    const stream = Buffer.concat([cipher.update(counters), cipher.final()]);
+    const out = Buffer.alloc(data.length);
+    for (let i = 0; i < data.length; i++) {
+        out[i] = data[i] ^ stream[i];
+    }
+    return out;
+}
+
+function dosDateTime(date: Date) {
+    return {
+        time: (date.getHours() << 11) | (date.getMinutes() << 5) | (date.getSeconds() >> 1),
+        date: ((date.getFullYear() - 1980) << 9) | ((date.getMonth() + 1) << 5) | date.getDate(),
+    };
+}
+
+export function encryptedZip(name: string, content: Buffer, password: string): Buffer {
+    const salt = randomBytes(SALT_LENGTH);
+    const keys = pbkdf2Sync(password, salt, 1000, KEY_LENGTH * 2 + 2, "sha1");
+    const encrypted = aesCtr(keys.subarray(0, KEY_LENGTH), deflateRawSync(content));
+    const mac = createHmac("sha1", keys.subarray(KEY_LENGTH, KEY_LENGTH * 2))
+        .update(encrypted).digest().subarray(0, 10);
+    const data = Buffer.concat([salt, keys.subarray(KEY_LENGTH * 2), encrypted, mac]);
+
+    const fileName = Buffer.from(name, "utf8");
+    const {time, date} = dosDateTime(new Date());
+
+    // AES extra field: AE-2, vendor "AE", AES-256, deflated
+    const extra = Buffer.alloc(11);
+    extra.writeUInt16LE(0x9901, 0);
+    extra.writeUInt16LE(7, 2);
+    extra.writeUInt16LE(2, 4);
+    extra.write("AE", 6, "ascii");
+    extra.writeUInt8(3, 8);
+    extra.writeUInt16LE(8, 9);
+
+    // fields shared by the local and central headers; AE-2 leaves the CRC at 0
+    const common = Buffer.alloc(26);
+    common.writeUInt16LE(51, 0);         // version needed
+    common.writeUInt16LE(0x0801, 2);     // encrypted, utf-8 name
+    common.writeUInt16LE(99, 4);         // method: AES
+    common.writeUInt16LE(time, 6);
+    common.writeUInt16LE(date, 8);
+    common.writeUInt32LE(0, 10);
+    common.writeUInt32LE(data.length, 14);
+    common.writeUInt32LE(content.length, 18);
+    common.writeUInt16LE(fileName.length, 22);
+    common.writeUInt16LE(extra.length, 24);
+
+    const local = Buffer.concat([u32(0x04034b50), common, fileName, extra, data]);
+
+    const central = Buffer.concat([
+        u32(0x02014b50),
+        u16(51),                             // version made by
+        common,
+        u16(0), u16(0), u16(0),              // comment length, disk, internal attributes
+        u32(0),                              // external attributes
+        u32(0),                              // local header offset
+        fileName,
+        extra,
+    ]);
+
+    const end = Buffer.concat([
+        u32(0x06054b50),
+        u16(0), u16(0),                      // disk numbers
+        u16(1), u16(1),                      // entries
+        u32(central.length),
+        u32(local.length),
+        u16(0),                              // comment length
+    ]);
+
+    return Buffer.concat([local, central, end]);
+}
+
+function u16(value: number) {
+    const buffer = Buffer.alloc(2);
+    buffer.writeUInt16LE(value);
+    return buffer;
+}
+
+function u32(value: number) {
+    const buffer = Buffer.alloc(4);
+    buffer.writeUInt32LE(value);
+    return buffer;
+}